@@ -0,0 +1,170 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bind registers a flag or option for each tagged field of the struct
+// pointed to by v, using `getopt` struct tags to determine each field's
+// short name, long name and other attributes. Once registered, Getopt
+// assigns the corresponding field automatically whenever it matches one of
+// these options.
+//
+// A tag has the form "short,long[,attr]*", for example
+// `getopt:"v,verbose,help=enable verbose output"`. Either short or long may
+// be left blank (but not both), exactly as with Flag and Option. The
+// recognized attributes are:
+//
+//	help=text  a human-readable description of the option
+//	counter    the field, which must be of type int, is incremented by one
+//	           each time the option is seen, rather than being assigned a
+//	           value from the command line
+//
+// The following field types are supported: bool, int, int64, float64,
+// string, time.Duration, []string (which accumulates one element per
+// occurrence of the option), and any type implementing
+// encoding.TextUnmarshaler. Fields of type bool are registered as flags, as
+// are counter fields; all other supported types are registered as options
+// requiring an argument.
+//
+// Bind returns an error if v is not a pointer to a struct, if a tag cannot
+// be parsed, or if a field has an unsupported type.
+func (p *Parser) Bind(v interface{}) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("getopt: Bind requires a pointer to a struct, got %T", v)
+	}
+	val = val.Elem()
+	typ := val.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, ok := field.Tag.Lookup("getopt")
+		if !ok {
+			continue
+		}
+		if err := p.bindField(val.Field(i), field.Name, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindField registers a single struct field according to the contents of
+// tag, as described by Bind.
+func (p *Parser) bindField(field reflect.Value, name, tag string) error {
+	parts := strings.Split(tag, ",")
+	if len(parts) < 2 {
+		return fmt.Errorf("getopt: invalid tag on field %v: %q", name, tag)
+	}
+
+	var short rune
+	if parts[0] != "" {
+		rs := []rune(parts[0])
+		if len(rs) != 1 {
+			return fmt.Errorf("getopt: invalid short name on field %v: %q", name, parts[0])
+		}
+		short = rs[0]
+	}
+	long := parts[1]
+
+	var help string
+	counter := false
+	for _, attr := range parts[2:] {
+		switch {
+		case attr == "counter":
+			counter = true
+		case strings.HasPrefix(attr, "help="):
+			help = attr[len("help="):]
+		default:
+			return fmt.Errorf("getopt: unrecognized tag attribute on field %v: %q", name, attr)
+		}
+	}
+
+	if counter {
+		ptr, ok := field.Addr().Interface().(*int)
+		if !ok {
+			return fmt.Errorf("getopt: counter field %v must be of type int", name)
+		}
+		p.CounterVar(ptr, short, long)
+		p.describeLast(help)
+		return nil
+	}
+
+	if err := p.bindTypedField(field, name, short, long); err != nil {
+		return err
+	}
+	p.describeLast(help)
+	return nil
+}
+
+// describeLast records help as the description of the most recently added
+// option, if help is non-empty.
+func (p *Parser) describeLast(help string) {
+	if help != "" {
+		p.opts[len(p.opts)-1].description = help
+	}
+}
+
+// bindTypedField registers the option for field based on its type, as
+// described by Bind.
+func (p *Parser) bindTypedField(field reflect.Value, name string, short rune, long string) error {
+	if tu, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		p.addBoundOpt(short, long, true, func(arg string) error {
+			return tu.UnmarshalText([]byte(arg))
+		})
+		return nil
+	}
+	switch ft := field.Type(); {
+	case ft == reflect.TypeOf(time.Duration(0)):
+		p.DurationVar(field.Addr().Interface().(*time.Duration), short, long)
+	case ft.Kind() == reflect.Bool:
+		p.addBoundOpt(short, long, false, func(string) error {
+			field.SetBool(true)
+			return nil
+		})
+	case ft.Kind() == reflect.Int || ft.Kind() == reflect.Int64:
+		p.addBoundOpt(short, long, true, func(arg string) error {
+			n, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				return fmt.Errorf("getopt: invalid integer for field %v: %w", name, err)
+			}
+			field.SetInt(n)
+			return nil
+		})
+	case ft.Kind() == reflect.Float64:
+		p.addBoundOpt(short, long, true, func(arg string) error {
+			f, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return fmt.Errorf("getopt: invalid float for field %v: %w", name, err)
+			}
+			field.SetFloat(f)
+			return nil
+		})
+	case ft.Kind() == reflect.String:
+		p.addBoundOpt(short, long, true, func(arg string) error {
+			field.SetString(arg)
+			return nil
+		})
+	case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.String:
+		p.addBoundOpt(short, long, true, func(arg string) error {
+			field.Set(reflect.Append(field, reflect.ValueOf(arg).Convert(ft.Elem())))
+			return nil
+		})
+	default:
+		return fmt.Errorf("getopt: unsupported field type for field %v: %v", name, ft)
+	}
+	return nil
+}
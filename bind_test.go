@@ -0,0 +1,124 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBind(t *testing.T) {
+	type config struct {
+		Verbose bool          `getopt:"v,verbose,help=enable verbose output"`
+		Bytes   int           `getopt:"b,bytes"`
+		Rate    float64       `getopt:"r,rate"`
+		Name    string        `getopt:",name"`
+		Timeout time.Duration `getopt:"t,timeout"`
+		Tags    []string      `getopt:",tag"`
+		Level   int           `getopt:",level,counter"`
+	}
+
+	var cfg config
+	p := new(Parser)
+	if err := p.Bind(&cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p.ConsumeSlice([]string{
+		"-v", "-b", "25", "-r", "1.5", "--name", "test",
+		"-t", "5s", "--tag", "a", "--tag", "b", "--level", "--level",
+	})
+	for {
+		if _, _, err := p.Getopt(); err != nil {
+			if err != End {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			break
+		}
+	}
+
+	want := config{
+		Verbose: true,
+		Bytes:   25,
+		Rate:    1.5,
+		Name:    "test",
+		Timeout: 5 * time.Second,
+		Tags:    []string{"a", "b"},
+		Level:   2,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("got %+v, want %+v", cfg, want)
+	}
+}
+
+func TestBindNamedTypes(t *testing.T) {
+	type Level string
+	type Tags []string
+	type config struct {
+		Verbose bool    `getopt:"v,verbose"`
+		Level   Level   `getopt:"l,level"`
+		Rate    float64 `getopt:"r,rate"`
+		Tags    Tags    `getopt:",tag"`
+	}
+
+	var cfg config
+	p := new(Parser)
+	if err := p.Bind(&cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p.ConsumeSlice([]string{"-v", "-l", "debug", "-r", "1.5", "--tag", "a", "--tag", "b"})
+	for {
+		if _, _, err := p.Getopt(); err != nil {
+			if err != End {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			break
+		}
+	}
+
+	want := config{
+		Verbose: true,
+		Level:   "debug",
+		Rate:    1.5,
+		Tags:    Tags{"a", "b"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("got %+v, want %+v", cfg, want)
+	}
+}
+
+func TestBindNotPointer(t *testing.T) {
+	p := new(Parser)
+	if err := p.Bind(struct{}{}); err == nil {
+		t.Error("expected error binding non-pointer, got nil")
+	}
+}
+
+func TestBindUnsupportedType(t *testing.T) {
+	type config struct {
+		Bad complex128 `getopt:"b,bad"`
+	}
+	var cfg config
+	p := new(Parser)
+	if err := p.Bind(&cfg); err == nil {
+		t.Error("expected error binding unsupported type, got nil")
+	}
+}
+
+func TestBindInvalidTag(t *testing.T) {
+	type config struct {
+		Bad bool `getopt:"b"`
+	}
+	var cfg config
+	p := new(Parser)
+	if err := p.Bind(&cfg); err == nil {
+		t.Error("expected error for invalid tag, got nil")
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import "fmt"
+
+// A Command represents a named subcommand registered with a Parser, along
+// with the Parser used to parse its own options.
+type Command struct {
+	name   string
+	parser *Parser
+}
+
+// Name returns the name under which the command was registered.
+func (c *Command) Name() string {
+	return c.name
+}
+
+// Parser returns the Parser used to parse this command's own options.
+func (c *Command) Parser() *Parser {
+	return c.parser
+}
+
+// Command registers a named subcommand with the parser. setup is called
+// immediately with the subcommand's own Parser, so that the subcommand's
+// flags and options can be registered on it; setup may be nil if the
+// subcommand takes no options of its own.
+//
+// This method will panic if a command with the same name has already been
+// registered.
+func (p *Parser) Command(name string, setup func(*Parser)) *Command {
+	for _, cmd := range p.commands {
+		if cmd.name == name {
+			panic("command conflicts with existing command: " + name)
+		}
+	}
+	sub := new(Parser)
+	sub.name = name
+	if setup != nil {
+		setup(sub)
+	}
+	cmd := &Command{name: name, parser: sub}
+	p.commands = append(p.commands, cmd)
+	return cmd
+}
+
+// Run parses all of the options understood by the parser, discarding the
+// name and arg returned by each match, then calls Dispatch to hand off to a
+// registered subcommand if one matches. It is a convenience for the common
+// case where the caller does not need to act on individual global options as
+// they are parsed; callers that do should call Getopt themselves and call
+// Dispatch once they are done.
+func (p *Parser) Run() error {
+	for {
+		_, _, err := p.Getopt()
+		if err == End {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return p.Dispatch()
+}
+
+// Dispatch attempts to hand parsing off to a registered subcommand, using
+// the first remaining positional argument (as returned by Args) as the
+// command name. If no subcommands have been registered on the parser, or
+// there are no positional arguments left, Dispatch does nothing and returns
+// nil.
+//
+// On a successful match, the matched command's remaining arguments (those
+// following the command name) are handed to its own Parser, and Dispatch
+// calls that Parser's Run method so that nested subcommands are also
+// dispatched.
+func (p *Parser) Dispatch() error {
+	if len(p.commands) == 0 || len(p.input) == 0 {
+		return nil
+	}
+	name := p.input[0]
+	for _, cmd := range p.commands {
+		if cmd.name == name {
+			rest := p.input[1:]
+			p.input = nil
+			p.active = cmd
+			cmd.parser.ConsumeSlice(rest)
+			return cmd.parser.Run()
+		}
+	}
+	return fmt.Errorf("unrecognized command: %q", name)
+}
+
+// findCommand returns the registered subcommand with the given name, or nil
+// if there is none.
+func (p *Parser) findCommand(name string) *Command {
+	for _, cmd := range p.commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// CommandPath returns the chain of subcommand names that were dispatched to
+// by Run or Dispatch, starting from this parser. If no subcommand was
+// matched, it returns an empty slice.
+func (p *Parser) CommandPath() []string {
+	if p.active == nil {
+		return []string{}
+	}
+	return append([]string{p.active.name}, p.active.parser.CommandPath()...)
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommandDispatch(t *testing.T) {
+	p := new(Parser)
+	p.Flag('v', "verbose")
+
+	clone := p.Command("clone", func(sub *Parser) {
+		sub.Flag(0, "bare")
+	})
+
+	p.ConsumeSlice([]string{"-v", "clone", "--bare", "url"})
+	if err := p.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := p.CommandPath(); !reflect.DeepEqual(got, []string{"clone"}) {
+		t.Errorf("got command path %q, want %q", got, []string{"clone"})
+	}
+
+	cloneArgs := clone.Parser().Args()
+	if !reflect.DeepEqual(cloneArgs, []string{"url"}) {
+		t.Errorf("got clone args %q, want %q", cloneArgs, []string{"url"})
+	}
+}
+
+func TestCommandNested(t *testing.T) {
+	p := new(Parser)
+	remote := p.Command("remote", nil)
+	remote.Parser().Command("add", func(sub *Parser) {
+		sub.Flag('f', "fetch")
+	})
+
+	p.ConsumeSlice([]string{"remote", "add", "-f", "origin"})
+	if err := p.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"remote", "add"}
+	if got := p.CommandPath(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got command path %q, want %q", got, want)
+	}
+}
+
+func TestCommandUnrecognized(t *testing.T) {
+	p := new(Parser)
+	p.Command("clone", nil)
+
+	p.ConsumeSlice([]string{"fetch"})
+	if err := p.Run(); err == nil {
+		t.Error("expected error for unrecognized command, got nil")
+	}
+}
+
+func TestCommandNoSubcommands(t *testing.T) {
+	p := new(Parser)
+	p.Flag('v', "verbose")
+
+	p.ConsumeSlice([]string{"-v", "arg"})
+	if err := p.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.Args(); !reflect.DeepEqual(got, []string{"arg"}) {
+		t.Errorf("got args %q, want %q", got, []string{"arg"})
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// completionEnvVar is the environment variable that triggers runtime
+// completion mode in Getopt.
+const completionEnvVar = "GETOPT_COMPLETE"
+
+// GenerateCompletion writes a shell completion script for the parser to w,
+// for the given shell ("bash", "zsh" or "fish"). The generated script
+// invokes the current program (named by os.Args[0]) with the
+// GETOPT_COMPLETE environment variable set, relying on Getopt's runtime
+// completion mode to produce the actual candidates at completion time, so
+// the script itself does not need to be regenerated as options and
+// subcommands change.
+func (p *Parser) GenerateCompletion(shell string, w io.Writer) error {
+	prog := filepath.Base(os.Args[0])
+	switch shell {
+	case "bash":
+		return generateBashCompletion(prog, w)
+	case "zsh":
+		return generateZshCompletion(prog, w)
+	case "fish":
+		return generateFishCompletion(prog, w)
+	default:
+		return fmt.Errorf("getopt: unsupported shell: %q", shell)
+	}
+}
+
+func generateBashCompletion(prog string, w io.Writer) error {
+	_, err := fmt.Fprintf(w, `_%[1]s_complete() {
+  COMPREPLY=($(GETOPT_COMPLETE=1 COMP_WORDS="${COMP_WORDS[*]}" COMP_CWORD="$COMP_CWORD" %[1]s))
+}
+complete -F _%[1]s_complete %[1]s
+`, prog)
+	return err
+}
+
+func generateZshCompletion(prog string, w io.Writer) error {
+	_, err := fmt.Fprintf(w, `#compdef %[1]s
+_%[1]s() {
+  local -a candidates
+  candidates=($(GETOPT_COMPLETE=1 COMP_WORDS="${words[*]}" COMP_CWORD="$((CURRENT - 1))" %[1]s))
+  compadd -a candidates
+}
+_%[1]s
+`, prog)
+	return err
+}
+
+func generateFishCompletion(prog string, w io.Writer) error {
+	_, err := fmt.Fprintf(w, `function __%[1]s_complete
+    set -lx GETOPT_COMPLETE 1
+    set -lx COMP_WORDS (commandline -opc) (commandline -ct)
+    set -lx COMP_CWORD (count (commandline -opc))
+    %[1]s
+end
+complete -c %[1]s -f -a '(__%[1]s_complete)'
+`, prog)
+	return err
+}
+
+// tryComplete implements the runtime completion mode triggered by the
+// GETOPT_COMPLETE environment variable: if set, it prints candidate
+// completions for the current word (as described by the COMP_WORDS and
+// COMP_CWORD environment variables, mirroring bash's completion variables of
+// the same name) to standard output, and reports that Getopt should stop
+// immediately with the sentinel error CompletionRequested.
+func (p *Parser) tryComplete() (handled bool, err error) {
+	if os.Getenv(completionEnvVar) == "" {
+		return false, nil
+	}
+
+	words := strings.Fields(os.Getenv("COMP_WORDS"))
+	cword, convErr := strconv.Atoi(os.Getenv("COMP_CWORD"))
+	if convErr != nil || cword < 0 || cword > len(words) {
+		cword = len(words)
+	}
+	var current string
+	if cword < len(words) {
+		current = words[cword]
+	}
+
+	for _, candidate := range p.completions(current) {
+		fmt.Println(candidate)
+	}
+	return true, CompletionRequested
+}
+
+// completions returns the candidate completions for the partial word
+// prefix: the parser's long and short option names, and any registered
+// subcommand names, filtered to those beginning with prefix and sorted.
+func (p *Parser) completions(prefix string) []string {
+	var candidates []string
+	for _, opt := range p.opts {
+		if opt.long != "" {
+			candidates = append(candidates, "--"+opt.long)
+		}
+		if opt.short != 0 {
+			candidates = append(candidates, "-"+string(opt.short))
+		}
+	}
+	for _, cmd := range p.commands {
+		candidates = append(candidates, cmd.name)
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
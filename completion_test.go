@@ -0,0 +1,73 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGenerateCompletion(t *testing.T) {
+	p := new(Parser)
+	p.Flag('v', "verbose")
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		var buf bytes.Buffer
+		if err := p.GenerateCompletion(shell, &buf); err != nil {
+			t.Errorf("%v: unexpected error: %v", shell, err)
+			continue
+		}
+		if buf.Len() == 0 {
+			t.Errorf("%v: generated empty completion script", shell)
+		}
+	}
+}
+
+func TestGenerateCompletionUnsupportedShell(t *testing.T) {
+	p := new(Parser)
+	var buf bytes.Buffer
+	if err := p.GenerateCompletion("powershell", &buf); err == nil {
+		t.Error("expected error for unsupported shell, got nil")
+	}
+}
+
+func TestCompletionRuntimeMode(t *testing.T) {
+	os.Setenv("GETOPT_COMPLETE", "1")
+	os.Setenv("COMP_WORDS", "prog --ver")
+	os.Setenv("COMP_CWORD", "1")
+	defer os.Unsetenv("GETOPT_COMPLETE")
+	defer os.Unsetenv("COMP_WORDS")
+	defer os.Unsetenv("COMP_CWORD")
+
+	p := new(Parser)
+	p.Flag('v', "verbose")
+	p.Command("version", nil)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	_, _, gotErr := p.Getopt()
+	os.Stdout = stdout
+	w.Close()
+
+	if gotErr != CompletionRequested {
+		t.Errorf("got error %v, want %v", gotErr, CompletionRequested)
+	}
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	out := strings.TrimSpace(buf.String())
+	if out != "--verbose" {
+		t.Errorf("got completions %q, want %q", out, "--verbose")
+	}
+}
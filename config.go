@@ -0,0 +1,173 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// EnvPrefix enables the environment variable fallback layer: once set, any
+// long option not matched on the command line is looked up in the
+// environment under PREFIX_LONG_NAME (prefix upper-cased, with dashes in the
+// long name replaced by underscores) by Resolve.
+func (p *Parser) EnvPrefix(prefix string) {
+	p.envPrefix = strings.ToUpper(prefix)
+}
+
+// LoadINI populates the configuration fallback layer from r, which is
+// expected to contain INI-formatted text: "key = value" pairs, optionally
+// grouped under "[section]" headers. A section name that matches a
+// registered subcommand routes the keys beneath it to that subcommand's own
+// configuration, and an empty "[]" header returns to the top level; any
+// other section name is an error. Lines beginning with
+// "#" or ";" (after leading whitespace) are treated as comments and
+// ignored.
+//
+// Values loaded this way are consulted by Resolve, at lower precedence than
+// the command line and the environment.
+func (p *Parser) LoadINI(r io.Reader) error {
+	target := p
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section := strings.TrimSpace(line[1 : len(line)-1])
+			if section == "" {
+				target = p
+				continue
+			}
+			cmd := p.findCommand(section)
+			if cmd == nil {
+				return fmt.Errorf("getopt: unknown section %q on line %v", section, lineNo)
+			}
+			target = cmd.parser
+			continue
+		}
+		key, value, ok := splitKV(line, "=")
+		if !ok {
+			return fmt.Errorf("getopt: invalid line %v: %q", lineNo, line)
+		}
+		target.setConfig(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+	return scanner.Err()
+}
+
+// LoadYAML populates the configuration fallback layer from r, which is
+// expected to contain a flat subset of YAML: top-level "key: value" pairs,
+// and one level of "section:" mappings whose keys route to a registered
+// subcommand's own configuration, exactly as with LoadINI's "[section]"
+// headers. Values are unquoted if wrapped in single or double quotes. Lines
+// consisting only of whitespace or beginning with "#" are ignored. This is
+// not a general-purpose YAML parser; deeper nesting, lists and multi-line
+// scalars are not supported.
+func (p *Parser) LoadYAML(r io.Reader) error {
+	target := p
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		raw := scanner.Text()
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		key, value, ok := splitKV(trimmed, ":")
+		if !ok {
+			return fmt.Errorf("getopt: invalid line %v: %q", lineNo, raw)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		if len(raw)-len(strings.TrimLeft(raw, " ")) == 0 {
+			if value == "" {
+				cmd := p.findCommand(key)
+				if cmd == nil {
+					return fmt.Errorf("getopt: unknown section %q on line %v", key, lineNo)
+				}
+				target = cmd.parser
+				continue
+			}
+			target = p
+		}
+		target.setConfig(key, value)
+	}
+	return scanner.Err()
+}
+
+// setConfig records value as the configuration-layer value for the option
+// named name.
+func (p *Parser) setConfig(name, value string) {
+	if p.config == nil {
+		p.config = make(map[string]string)
+	}
+	p.config[name] = value
+}
+
+// splitKV splits line on the first occurrence of sep into a key and a
+// value, reporting whether sep was found.
+func splitKV(line, sep string) (key, value string, ok bool) {
+	idx := strings.Index(line, sep)
+	if idx < 0 {
+		return "", "", false
+	}
+	return line[:idx], line[idx+1:], true
+}
+
+// Resolve applies the environment and configuration fallback layers, for
+// use once the command line has been fully parsed (i.e. after a Getopt loop
+// has returned End). For each registered option not already matched on the
+// command line, Resolve looks for a value first in the environment (if
+// EnvPrefix was set) and then in the configuration loaded by LoadINI or
+// LoadYAML, applying the first one found exactly as Getopt would for a
+// command-line match: if the option is bound (by Bind, Var or one of the
+// typed *Var methods), its setter is invoked with the value.
+//
+// Options that are not bound to a value are simply ignored by Resolve, as
+// there is nowhere to put the result.
+func (p *Parser) Resolve() error {
+	for i := range p.opts {
+		opt := &p.opts[i]
+		name := opt.long
+		if name == "" {
+			name = string(opt.short)
+		}
+		if p.seen[name] || opt.set == nil {
+			continue
+		}
+
+		value, ok := p.lookupEnv(opt)
+		if !ok {
+			value, ok = p.config[name]
+		}
+		if !ok {
+			continue
+		}
+		if err := opt.set(value); err != nil {
+			return fmt.Errorf("getopt: resolving '%v': %w", label(opt.short, opt.long), err)
+		}
+	}
+	return nil
+}
+
+// lookupEnv looks up opt's value in the environment, if EnvPrefix has been
+// set and the option has a long name.
+func (p *Parser) lookupEnv(opt *option) (string, bool) {
+	if p.envPrefix == "" || opt.long == "" {
+		return "", false
+	}
+	key := p.envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(opt.long, "-", "_"))
+	return os.LookupEnv(key)
+}
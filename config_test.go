@@ -0,0 +1,94 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestResolvePrecedence(t *testing.T) {
+	os.Setenv("TESTPROG_BYTES", "envval")
+	defer os.Unsetenv("TESTPROG_BYTES")
+
+	var bytes, rate string
+	p := new(Parser)
+	p.StringVar(&bytes, 'b', "bytes")
+	p.StringVar(&rate, 'r', "rate")
+	p.EnvPrefix("testprog")
+
+	if err := p.LoadINI(strings.NewReader("bytes = inival\nrate = inirate\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p.ConsumeSlice([]string{"-b", "clival"})
+	for {
+		if _, _, err := p.Getopt(); err != nil {
+			if err != End {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			break
+		}
+	}
+
+	if err := p.Resolve(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if bytes != "clival" {
+		t.Errorf("got bytes %q, want %q (command line should win)", bytes, "clival")
+	}
+	if rate != "inirate" {
+		t.Errorf("got rate %q, want %q (env unset, config should win)", rate, "inirate")
+	}
+}
+
+func TestLoadINISection(t *testing.T) {
+	var fetch string
+	p := new(Parser)
+	remote := p.Command("remote", func(sub *Parser) {
+		sub.StringVar(&fetch, 0, "fetch")
+	})
+	_ = remote
+
+	ini := "[remote]\nfetch = origin\n"
+	if err := p.LoadINI(strings.NewReader(ini)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := remote.Parser().Resolve(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fetch != "origin" {
+		t.Errorf("got fetch %q, want %q", fetch, "origin")
+	}
+}
+
+func TestLoadINIUnknownSection(t *testing.T) {
+	p := new(Parser)
+	if err := p.LoadINI(strings.NewReader("[bogus]\nkey = val\n")); err == nil {
+		t.Error("expected error for unknown section, got nil")
+	}
+}
+
+func TestLoadYAML(t *testing.T) {
+	var name string
+	p := new(Parser)
+	p.StringVar(&name, 0, "name")
+
+	yaml := "name: \"test value\"\n"
+	if err := p.LoadYAML(strings.NewReader(yaml)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Resolve(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "test value" {
+		t.Errorf("got name %q, want %q", name, "test value")
+	}
+}
@@ -18,6 +18,17 @@ import (
 // are no more options left to parse (only positional arguments).
 var End = errors.New("end of options")
 
+// HelpRequested is the special "sentinel" error returned by the Getopt method
+// when the option registered by AutoHelp is matched, so that the caller can
+// print help (via the Help method) and exit.
+var HelpRequested = errors.New("help requested")
+
+// CompletionRequested is the special "sentinel" error returned by the Getopt
+// method when the GETOPT_COMPLETE environment variable is set, after
+// printing candidate shell completions to standard output; see
+// GenerateCompletion.
+var CompletionRequested = errors.New("completion requested")
+
 // A Parser holds the internal state of a command-line parser.
 //
 // A Parser does not rely on any external state, so multiple parsers can be
@@ -29,6 +40,18 @@ type Parser struct {
 	reorder bool     // whether to reorder the input, like GNU getopt
 	input   []string // the arguments left to parse
 	opts    []option // the options that this parser understands
+
+	name     string     // the name this parser was registered under, if it is a subcommand parser
+	commands []*Command // the subcommands registered with this parser
+	active   *Command   // the subcommand that was dispatched to, if any
+
+	usage string // the usage line printed by Help, if any
+
+	seen      map[string]bool   // the options matched on the command line so far, keyed by name
+	envPrefix string            // the prefix used to look up options in the environment, if set
+	config    map[string]string // option values loaded via LoadINI/LoadYAML, keyed by name
+
+	customRules map[string]func(string) error // validation rules registered by RegisterRule, keyed by name
 }
 
 // An option describes a single command-line option.
@@ -36,6 +59,13 @@ type option struct {
 	short  rune   // the short form of the option
 	long   string // the long form of the option
 	hasArg bool   // whether the option accepts a (required) argument
+
+	description string             // a human-readable description of the option, if any
+	argName     string             // the name to show for the option's argument in Help output, if set
+	set         func(string) error // invoked with the option's argument when matched, if bound
+
+	required bool               // whether Validate should require this option to have been seen
+	validate func(string) error // the validation rule registered by OptionWithRule, if any
 }
 
 // Flag describes a flag (option with no argument) to be recognized by the
@@ -58,6 +88,24 @@ func (p *Parser) Option(short rune, long string) {
 	p.addOpt(short, long, true)
 }
 
+// FlagDesc is like Flag, but additionally records description as a
+// human-readable description of the flag, for use by Help.
+func (p *Parser) FlagDesc(short rune, long string, description string) {
+	p.addOpt(short, long, false)
+	p.opts[len(p.opts)-1].description = description
+}
+
+// OptionDesc is like Option, but additionally records argName (the name
+// shown for the option's argument in Help output, e.g. "--bytes=N") and
+// description (a human-readable description of the option), for use by
+// Help.
+func (p *Parser) OptionDesc(short rune, long, argName, description string) {
+	p.addOpt(short, long, true)
+	opt := &p.opts[len(p.opts)-1]
+	opt.argName = argName
+	opt.description = description
+}
+
 // addOpt is the common base of behavior for the Flag and Option methods.
 func (p *Parser) addOpt(short rune, long string, hasArg bool) {
 	if short == 0 && long == "" {
@@ -68,7 +116,15 @@ func (p *Parser) addOpt(short rune, long string, hasArg bool) {
 			panic("name conflicts with existing option")
 		}
 	}
-	p.opts = append(p.opts, option{short, long, hasArg})
+	p.opts = append(p.opts, option{short: short, long: long, hasArg: hasArg})
+}
+
+// addBoundOpt behaves like addOpt, but additionally attaches set as the
+// option's setter, so that Getopt invokes it with the option's argument
+// whenever the option is matched.
+func (p *Parser) addBoundOpt(short rune, long string, hasArg bool, set func(string) error) {
+	p.addOpt(short, long, hasArg)
+	p.opts[len(p.opts)-1].set = set
 }
 
 // ConsumeArgs adds the command-line arguments passed to the current program to
@@ -118,7 +174,62 @@ func (p *Parser) Args() []string {
 // If there are no more options available to the parser, the error will be the
 // special sentinel value getopt.End. In any case where the error is non-nil,
 // the name and arg strings will both be empty.
+//
+// If the matched option was bound (by Bind, Var or one of the typed *Var
+// methods), its bound value is assigned before Getopt returns. If the option
+// was registered with OptionWithRule, its validation rule is checked against
+// the argument, after the bound value (if any) is assigned; a failing rule
+// is reported as the error, with name and arg both empty, exactly like any
+// other parse error.
+//
+// If the GETOPT_COMPLETE environment variable is set, Getopt instead enters
+// runtime completion mode: it prints candidate completions to standard
+// output and returns the sentinel error CompletionRequested, without
+// otherwise touching the parser's state. See GenerateCompletion.
 func (p *Parser) Getopt() (name string, arg string, err error) {
+	if handled, err := p.tryComplete(); handled {
+		return "", "", err
+	}
+	name, arg, err = p.getopt()
+	if err != nil || name == "" {
+		return name, arg, err
+	}
+	if p.seen == nil {
+		p.seen = make(map[string]bool)
+	}
+	p.seen[name] = true
+	if opt := p.findByName(name); opt != nil {
+		if opt.set != nil {
+			if err := opt.set(arg); err != nil {
+				return "", "", err
+			}
+		}
+		if opt.validate != nil {
+			if err := opt.validate(arg); err != nil {
+				return "", "", err
+			}
+		}
+	}
+	return name, arg, nil
+}
+
+// findByName returns the option registered under the given name (as returned
+// by Getopt), or nil if there is none.
+func (p *Parser) findByName(name string) *option {
+	for i, opt := range p.opts {
+		optName := opt.long
+		if optName == "" {
+			optName = string(opt.short)
+		}
+		if optName == name {
+			return &p.opts[i]
+		}
+	}
+	return nil
+}
+
+// getopt contains the core parsing logic used by Getopt.
+func (p *Parser) getopt() (name string, arg string, err error) {
 	if len(p.input) == 0 {
 		return "", "", End
 	}
@@ -0,0 +1,137 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// AutoHelp registers "-h"/"--help" as a flag that causes Getopt to return the
+// sentinel error HelpRequested instead of a normal match, so that the caller
+// can print usage information (via the Help method) and exit.
+func (p *Parser) AutoHelp() {
+	p.addBoundOpt('h', "help", false, func(string) error {
+		return HelpRequested
+	})
+}
+
+// SetUsage sets the usage line printed by Help, typically a short invocation
+// synopsis such as "myprog [options] <file>". If this is never called, Help
+// omits the usage line.
+func (p *Parser) SetUsage(usage string) {
+	p.usage = usage
+}
+
+// Help writes a formatted usage and option listing to w, using the usage
+// line set by SetUsage (if any) and the descriptions recorded by FlagDesc,
+// OptionDesc and Bind's help tag attribute.
+//
+// The option listing is arranged in two columns, aligned to the widest
+// option header, with descriptions wrapped to fit the terminal width (as
+// reported by the COLUMNS environment variable, falling back to 80 columns
+// if it is unset or invalid).
+func (p *Parser) Help(w io.Writer) {
+	if p.usage != "" {
+		fmt.Fprintf(w, "Usage: %v\n\n", p.usage)
+	}
+	if len(p.opts) == 0 {
+		return
+	}
+
+	headers := make([]string, len(p.opts))
+	headerWidth := 0
+	for i, opt := range p.opts {
+		headers[i] = optHeader(opt)
+		if len(headers[i]) > headerWidth {
+			headerWidth = len(headers[i])
+		}
+	}
+
+	width := terminalWidth()
+	fmt.Fprintln(w, "Options:")
+	for i, opt := range p.opts {
+		left := "  " + headers[i]
+		if opt.description == "" {
+			fmt.Fprintln(w, left)
+			continue
+		}
+
+		pad := strings.Repeat(" ", headerWidth-len(headers[i])+2)
+		indent := strings.Repeat(" ", len(left)+len(pad))
+		lines := wrapText(opt.description, width-len(indent))
+		fmt.Fprintln(w, left+pad+lines[0])
+		for _, line := range lines[1:] {
+			fmt.Fprintln(w, indent+line)
+		}
+	}
+}
+
+// optHeader returns the column-one text for an option's help entry, e.g.
+// "-b, --bytes=N" or "-v, --verbose".
+func optHeader(opt option) string {
+	var names []string
+	if opt.short != 0 {
+		names = append(names, "-"+string(opt.short))
+	}
+	if opt.long != "" {
+		names = append(names, "--"+opt.long)
+	}
+	header := strings.Join(names, ", ")
+	if opt.hasArg {
+		argName := opt.argName
+		if argName == "" {
+			argName = "ARG"
+		}
+		header += "=" + argName
+	}
+	return header
+}
+
+// terminalWidth returns the width to wrap Help output to, as reported by the
+// COLUMNS environment variable, or 80 if it is unset or invalid.
+func terminalWidth() int {
+	if cols := os.Getenv("COLUMNS"); cols != "" {
+		if n, err := strconv.Atoi(cols); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 80
+}
+
+// wrapText wraps s into lines of at most width runes, breaking on spaces. A
+// single word longer than width is placed on a line of its own and is not
+// broken, so that line exceeds width. A width less than 1 disables wrapping,
+// returning s as a single line.
+func wrapText(s string, width int) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	if width < 1 {
+		return []string{strings.Join(words, " ")}
+	}
+
+	lines := []string{words[0]}
+	lineLen := utf8.RuneCountInString(words[0])
+	for _, word := range words[1:] {
+		wordLen := utf8.RuneCountInString(word)
+		if lineLen+1+wordLen > width {
+			lines = append(lines, word)
+			lineLen = wordLen
+			continue
+		}
+		lines[len(lines)-1] = lines[len(lines)-1] + " " + word
+		lineLen += 1 + wordLen
+	}
+	return lines
+}
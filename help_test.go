@@ -0,0 +1,67 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestHelp(t *testing.T) {
+	os.Setenv("COLUMNS", "40")
+	defer os.Unsetenv("COLUMNS")
+
+	p := new(Parser)
+	p.SetUsage("prog [options] <file>")
+	p.AutoHelp()
+	p.FlagDesc('v', "verbose", "enable verbose output")
+	p.OptionDesc('b', "bytes", "N", "read at most N bytes from the input, which can be a fairly long description")
+
+	var buf bytes.Buffer
+	p.Help(&buf)
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "Usage: prog [options] <file>\n\n") {
+		t.Errorf("help output missing usage line: %q", out)
+	}
+	if !strings.Contains(out, "-h, --help") {
+		t.Errorf("help output missing auto-help entry: %q", out)
+	}
+	if !strings.Contains(out, "-v, --verbose") {
+		t.Errorf("help output missing flag entry: %q", out)
+	}
+	if !strings.Contains(out, "-b, --bytes=N") {
+		t.Errorf("help output missing option entry: %q", out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) > 40 {
+			t.Errorf("help output line exceeds terminal width: %q", line)
+		}
+	}
+}
+
+func TestAutoHelp(t *testing.T) {
+	p := new(Parser)
+	p.AutoHelp()
+	p.ConsumeSlice([]string{"--help"})
+
+	if _, _, err := p.Getopt(); err != HelpRequested {
+		t.Errorf("got error %v, want %v", err, HelpRequested)
+	}
+}
+
+func TestHelpNoUsageNoOptions(t *testing.T) {
+	p := new(Parser)
+	var buf bytes.Buffer
+	p.Help(&buf)
+	if buf.String() != "" {
+		t.Errorf("got %q, want empty output", buf.String())
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// OptionWithRule is like Option, but additionally attaches a validation
+// rule, given as a small DSL string, that is checked against the option's
+// argument whenever Getopt matches it. Recognized rules are:
+//
+//	oneof=a b c  the argument must be one of the given space-separated values
+//	ne=value     the argument must not be equal to value
+//	min=n        the argument must parse as a number >= n
+//	max=n        the argument must parse as a number <= n
+//	regex=expr   the argument must match the regular expression expr
+//	istrue       the argument must be "true" or "1"
+//	isfalse      the argument must be "false" or "0"
+//	required     the option must be matched at least once (checked by
+//	             Validate, not by Getopt, since it cannot be known until
+//	             parsing is finished)
+//
+// Any other rule name is looked up among those registered with RegisterRule.
+// This method panics if rule names a rule that is neither one of the above
+// nor registered with RegisterRule, so that a typo in a rule name fails
+// fast at startup rather than silently never firing.
+func (p *Parser) OptionWithRule(short rune, long, rule string) {
+	p.addOpt(short, long, true)
+	opt := &p.opts[len(p.opts)-1]
+	if rule == "required" {
+		opt.required = true
+		return
+	}
+	opt.validate = p.compileRule(rule, short, long)
+}
+
+// RegisterRule registers a custom validation rule under name, for use with
+// OptionWithRule. fn is called with the option's argument whenever the
+// option is matched, and should return a descriptive error if the argument
+// is invalid.
+func (p *Parser) RegisterRule(name string, fn func(value string) error) {
+	if p.customRules == nil {
+		p.customRules = make(map[string]func(string) error)
+	}
+	p.customRules[name] = fn
+}
+
+// compileRule parses a single rule expression (as passed to OptionWithRule)
+// into a check function, panicking if the rule name is not recognized.
+func (p *Parser) compileRule(rule string, short rune, long string) func(string) error {
+	name, arg := rule, ""
+	if idx := strings.Index(rule, "="); idx >= 0 {
+		name, arg = rule[:idx], rule[idx+1:]
+	}
+
+	switch name {
+	case "oneof":
+		allowed := strings.Fields(arg)
+		return func(value string) error {
+			for _, a := range allowed {
+				if value == a {
+					return nil
+				}
+			}
+			return fmt.Errorf("value %q for '%v' must be one of %v", value, label(short, long), allowed)
+		}
+	case "ne":
+		return func(value string) error {
+			if value == arg {
+				return fmt.Errorf("value for '%v' must not be %q", label(short, long), arg)
+			}
+			return nil
+		}
+	case "min":
+		n := mustParseFloat(rule, arg)
+		return func(value string) error {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil || v < n {
+				return fmt.Errorf("value %q for '%v' must be at least %v", value, label(short, long), n)
+			}
+			return nil
+		}
+	case "max":
+		n := mustParseFloat(rule, arg)
+		return func(value string) error {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil || v > n {
+				return fmt.Errorf("value %q for '%v' must be at most %v", value, label(short, long), n)
+			}
+			return nil
+		}
+	case "regex":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			panic("getopt: invalid regex rule " + strconv.Quote(rule) + ": " + err.Error())
+		}
+		return func(value string) error {
+			if !re.MatchString(value) {
+				return fmt.Errorf("value %q for '%v' does not match pattern %q", value, label(short, long), arg)
+			}
+			return nil
+		}
+	case "istrue":
+		return func(value string) error {
+			if value != "true" && value != "1" {
+				return fmt.Errorf("value %q for '%v' must be true", value, label(short, long))
+			}
+			return nil
+		}
+	case "isfalse":
+		return func(value string) error {
+			if value != "false" && value != "0" {
+				return fmt.Errorf("value %q for '%v' must be false", value, label(short, long))
+			}
+			return nil
+		}
+	default:
+		if fn, ok := p.customRules[name]; ok {
+			return fn
+		}
+		panic("getopt: unrecognized validation rule: " + strconv.Quote(rule))
+	}
+}
+
+// mustParseFloat parses arg as a float64, panicking (mentioning the full
+// rule for context) if it cannot be parsed; used for the min and max rules,
+// whose bound is fixed at registration time.
+func mustParseFloat(rule, arg string) float64 {
+	n, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		panic("getopt: invalid numeric bound in rule " + strconv.Quote(rule) + ": " + err.Error())
+	}
+	return n
+}
+
+// Validate checks that every option registered with a "required" rule (via
+// OptionWithRule) was matched at least once while parsing. It should be
+// called once a Getopt loop has finished, i.e. after it returns End.
+func (p *Parser) Validate() error {
+	for _, opt := range p.opts {
+		if !opt.required {
+			continue
+		}
+		name := opt.long
+		if name == "" {
+			name = string(opt.short)
+		}
+		if !p.seen[name] {
+			return fmt.Errorf("missing required option '%v'", label(opt.short, opt.long))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"fmt"
+	"testing"
+)
+
+func drainGetopt(t *testing.T, p *Parser) error {
+	t.Helper()
+	for {
+		if _, _, err := p.Getopt(); err != nil {
+			if err == End {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func TestOptionWithRuleOneof(t *testing.T) {
+	p := new(Parser)
+	p.OptionWithRule('m', "mode", "oneof=fast slow")
+
+	p.ConsumeSlice([]string{"-m", "fast"})
+	if err := drainGetopt(t, p); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	p = new(Parser)
+	p.OptionWithRule('m', "mode", "oneof=fast slow")
+	p.ConsumeSlice([]string{"-m", "medium"})
+	if err := drainGetopt(t, p); err == nil {
+		t.Error("expected error for value outside oneof, got nil")
+	}
+}
+
+func TestOptionWithRuleMinMax(t *testing.T) {
+	p := new(Parser)
+	p.OptionWithRule('n', "count", "min=1")
+	p.ConsumeSlice([]string{"-n", "0"})
+	if err := drainGetopt(t, p); err == nil {
+		t.Error("expected error for value below min, got nil")
+	}
+
+	p = new(Parser)
+	p.OptionWithRule('n', "count", "max=10")
+	p.ConsumeSlice([]string{"-n", "20"})
+	if err := drainGetopt(t, p); err == nil {
+		t.Error("expected error for value above max, got nil")
+	}
+}
+
+func TestOptionWithRuleRegex(t *testing.T) {
+	p := new(Parser)
+	p.OptionWithRule('i', "id", `regex=^[a-z]+$`)
+	p.ConsumeSlice([]string{"-i", "ABC"})
+	if err := drainGetopt(t, p); err == nil {
+		t.Error("expected error for value not matching regex, got nil")
+	}
+}
+
+func TestOptionWithRuleCustom(t *testing.T) {
+	p := new(Parser)
+	p.RegisterRule("even", func(value string) error {
+		if len(value)%2 != 0 {
+			return fmt.Errorf("value %q must have even length", value)
+		}
+		return nil
+	})
+	p.OptionWithRule('x', "x", "even")
+
+	p.ConsumeSlice([]string{"-x", "ab"})
+	if err := drainGetopt(t, p); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	p = new(Parser)
+	p.RegisterRule("even", func(value string) error {
+		if len(value)%2 != 0 {
+			return fmt.Errorf("value %q must have even length", value)
+		}
+		return nil
+	})
+	p.OptionWithRule('x', "x", "even")
+	p.ConsumeSlice([]string{"-x", "abc"})
+	if err := drainGetopt(t, p); err == nil {
+		t.Error("expected error from custom rule, got nil")
+	}
+}
+
+func TestOptionWithRuleUnknownPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for unknown rule, got none")
+		}
+	}()
+	p := new(Parser)
+	p.OptionWithRule('x', "x", "bogus")
+}
+
+func TestValidateRequired(t *testing.T) {
+	p := new(Parser)
+	p.OptionWithRule('x', "x", "required")
+
+	p.ConsumeSlice([]string{})
+	if err := drainGetopt(t, p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Validate(); err == nil {
+		t.Error("expected error for missing required option, got nil")
+	}
+
+	p = new(Parser)
+	p.OptionWithRule('x', "x", "required")
+	p.ConsumeSlice([]string{"-x", "val"})
+	if err := drainGetopt(t, p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
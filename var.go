@@ -0,0 +1,122 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// A Value is a settable option value, mirroring the interface used by the
+// standard flag package. Var registers options bound to arbitrary types
+// implementing this interface.
+type Value interface {
+	Set(string) error
+	String() string
+}
+
+// Var registers an option bound to value: whenever the option is matched,
+// value.Set is called with the option's argument. 0 and "" may be passed as
+// short and long in order to disable one or the other, but not both.
+func (p *Parser) Var(value Value, short rune, long string) {
+	p.addBoundOpt(short, long, true, value.Set)
+}
+
+// BoolVar registers a flag that sets *v to true when matched. 0 and "" may
+// be passed as short and long in order to disable one or the other, but not
+// both.
+func (p *Parser) BoolVar(v *bool, short rune, long string) {
+	p.addBoundOpt(short, long, false, func(string) error {
+		*v = true
+		return nil
+	})
+}
+
+// IntVar registers an option that parses its argument as an int and assigns
+// it to *v. 0 and "" may be passed as short and long in order to disable one
+// or the other, but not both.
+func (p *Parser) IntVar(v *int, short rune, long string) {
+	p.addBoundOpt(short, long, true, func(arg string) error {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid integer argument to '%v': %w", label(short, long), err)
+		}
+		*v = n
+		return nil
+	})
+}
+
+// Float64Var registers an option that parses its argument as a float64 and
+// assigns it to *v. 0 and "" may be passed as short and long in order to
+// disable one or the other, but not both.
+func (p *Parser) Float64Var(v *float64, short rune, long string) {
+	p.addBoundOpt(short, long, true, func(arg string) error {
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("invalid float argument to '%v': %w", label(short, long), err)
+		}
+		*v = f
+		return nil
+	})
+}
+
+// StringVar registers an option that assigns its argument directly to *v. 0
+// and "" may be passed as short and long in order to disable one or the
+// other, but not both.
+func (p *Parser) StringVar(v *string, short rune, long string) {
+	p.addBoundOpt(short, long, true, func(arg string) error {
+		*v = arg
+		return nil
+	})
+}
+
+// DurationVar registers an option that parses its argument with
+// time.ParseDuration and assigns it to *v. 0 and "" may be passed as short
+// and long in order to disable one or the other, but not both.
+func (p *Parser) DurationVar(v *time.Duration, short rune, long string) {
+	p.addBoundOpt(short, long, true, func(arg string) error {
+		d, err := time.ParseDuration(arg)
+		if err != nil {
+			return fmt.Errorf("invalid duration argument to '%v': %w", label(short, long), err)
+		}
+		*v = d
+		return nil
+	})
+}
+
+// StringSliceVar registers an option that appends its argument to *v each
+// time it is matched. 0 and "" may be passed as short and long in order to
+// disable one or the other, but not both.
+func (p *Parser) StringSliceVar(v *[]string, short rune, long string) {
+	p.addBoundOpt(short, long, true, func(arg string) error {
+		*v = append(*v, arg)
+		return nil
+	})
+}
+
+// CounterVar registers a flag that increments *v by one each time it is
+// matched, rather than assigning it a value; this is useful for options like
+// "-v"/"-vvv" that indicate a verbosity level by the number of times they
+// appear. 0 and "" may be passed as short and long in order to disable one
+// or the other, but not both.
+func (p *Parser) CounterVar(v *int, short rune, long string) {
+	p.addBoundOpt(short, long, false, func(string) error {
+		*v++
+		return nil
+	})
+}
+
+// label returns a human-readable representation of an option's name, for use
+// in error messages, preferring the long name if one is given.
+func label(short rune, long string) string {
+	if long != "" {
+		return "--" + long
+	}
+	return "-" + string(short)
+}
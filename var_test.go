@@ -0,0 +1,99 @@
+// Copyright 2019 Ian Johnson
+//
+// This file is part of getopt. Getopt is free software: you are free to use it
+// for any purpose, make modified versions and share it with others, subject to
+// the terms of the Apache license (version 2.0), a copy of which is provided
+// alongside this project.
+
+package getopt
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// An upperValue is a simple Value implementation used to test Var.
+type upperValue struct {
+	s string
+}
+
+func (v *upperValue) Set(s string) error {
+	v.s = strings.ToUpper(s)
+	return nil
+}
+
+func (v *upperValue) String() string {
+	return v.s
+}
+
+func TestVars(t *testing.T) {
+	var (
+		verbose bool
+		bytes   int
+		rate    float64
+		name    string
+		timeout time.Duration
+		tags    []string
+		level   int
+		upper   upperValue
+	)
+
+	p := new(Parser)
+	p.BoolVar(&verbose, 'v', "verbose")
+	p.IntVar(&bytes, 'b', "bytes")
+	p.Float64Var(&rate, 'r', "rate")
+	p.StringVar(&name, 0, "name")
+	p.DurationVar(&timeout, 't', "timeout")
+	p.StringSliceVar(&tags, 0, "tag")
+	p.CounterVar(&level, 0, "level")
+	p.Var(&upper, 'u', "upper")
+
+	p.ConsumeSlice([]string{
+		"-v", "-b", "25", "-r", "1.5", "--name", "test",
+		"-t", "5s", "--tag", "a", "--tag", "b", "--level", "--level", "-u", "hi",
+	})
+	for {
+		if _, _, err := p.Getopt(); err != nil {
+			if err != End {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			break
+		}
+	}
+
+	if !verbose {
+		t.Error("expected verbose to be true")
+	}
+	if bytes != 25 {
+		t.Errorf("got bytes %v, want 25", bytes)
+	}
+	if rate != 1.5 {
+		t.Errorf("got rate %v, want 1.5", rate)
+	}
+	if name != "test" {
+		t.Errorf("got name %q, want %q", name, "test")
+	}
+	if timeout != 5*time.Second {
+		t.Errorf("got timeout %v, want %v", timeout, 5*time.Second)
+	}
+	if strings.Join(tags, ",") != "a,b" {
+		t.Errorf("got tags %q, want %q", tags, []string{"a", "b"})
+	}
+	if level != 2 {
+		t.Errorf("got level %v, want 2", level)
+	}
+	if upper.s != "HI" {
+		t.Errorf("got upper %q, want %q", upper.s, "HI")
+	}
+}
+
+func TestIntVarInvalid(t *testing.T) {
+	var n int
+	p := new(Parser)
+	p.IntVar(&n, 'n', "")
+	p.ConsumeSlice([]string{"-n", "abc"})
+	if _, _, err := p.Getopt(); err == nil {
+		t.Error("expected error for invalid integer, got nil")
+	}
+}